@@ -1,8 +1,22 @@
 package main
 
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"time"
+	"weatherservices/services/advisor"
+	"weatherservices/shared/proto/advisorpb"
+
+	"google.golang.org/grpc"
+)
+
 var (
 	serverAddr = "localhost:8082"
 
+	cacheBackend = flag.String("cache-backend", "memory", "cache backend for geocoding/weather lookups: memory or redis")
+
 	availableCities = map[string][2]float64{
 		"New York":      {40.7128, -74.0060},
 		"London":        {51.5074, -0.1278},
@@ -22,6 +36,89 @@ var (
 	}
 )
 
+// newWeatherProviderFromEnv builds the WeatherProvider the advisor service
+// should use, selected via the WEATHER_PROVIDER env var: "nws" for US-only
+// NWS, "owm" for OpenWeatherMap, "open-meteo" for Open-Meteo (the default),
+// or "multi" to route US coordinates to NWS and everything else to the
+// configured fallback.
+func newWeatherProviderFromEnv() advisor.WeatherProvider {
+	openMeteo := advisor.NewOpenMeteoProvider()
+	owm := advisor.NewOpenWeatherMapProvider(advisor.OpenWeatherMapConfig{
+		AppID: os.Getenv("OWM_APP_ID"),
+		Units: os.Getenv("OWM_UNITS"),
+		Lang:  os.Getenv("OWM_LANG"),
+	})
+	nws := advisor.NewNWSProvider(os.Getenv("NWS_CONTACT_EMAIL"))
+
+	switch os.Getenv("WEATHER_PROVIDER") {
+	case "nws":
+		return nws
+	case "owm":
+		return owm
+	case "multi":
+		fallback := advisor.WeatherProvider(openMeteo)
+		if os.Getenv("OWM_APP_ID") != "" {
+			fallback = owm
+		}
+		return advisor.NewMultiProvider(nws, fallback)
+	default:
+		return openMeteo
+	}
+}
+
+// newGeocoderFromEnv builds the geocoder chain advisorService resolves
+// cities through: Nominatim first (most accurate, but rate-limited),
+// Open-Meteo next, then the static availableCities lookup as a last
+// resort that never makes a network call.
+func newGeocoderFromEnv() *advisor.ChainGeocoder {
+	return advisor.NewChainGeocoder(5*time.Second,
+		advisor.NewNominatimGeocoder(os.Getenv("GEOCODER_CONTACT_EMAIL")),
+		advisor.NewOpenMeteoGeocoder(),
+		advisor.NewStaticGeocoder(availableCities),
+	)
+}
+
+// newCacheFromFlag builds the Cache backend selected by --cache-backend.
+// "redis" requires REDIS_ADDR to be set; anything else falls back to the
+// in-memory LRU.
+func newCacheFromFlag(backend string) advisor.Cache {
+	if backend == "redis" {
+		if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+			return advisor.NewRedisCache(addr)
+		}
+	}
+	return advisor.NewInMemoryCache(0)
+}
+
 func main() {
+	flag.Parse()
+
+	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
+	if geminiAPIKey == "" {
+		log.Fatal("GEMINI_API_KEY must be set")
+	}
+
+	svc, err := advisor.NewAdvisorService(
+		newWeatherProviderFromEnv(),
+		newGeocoderFromEnv(),
+		geminiAPIKey,
+		newCacheFromFlag(*cacheBackend),
+	)
+	if err != nil {
+		log.Fatalf("failed to create advisor service: %v", err)
+	}
+	defer svc.Close()
+
+	lis, err := net.Listen("tcp", serverAddr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", serverAddr, err)
+	}
 
+	grpcServer := grpc.NewServer()
+	advisorpb.RegisterAdvisorServiceServer(grpcServer, svc)
+
+	log.Printf("advisor service listening on %s", serverAddr)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("advisor service stopped: %v", err)
+	}
 }
@@ -0,0 +1,216 @@
+package advisor
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"weatherservices/shared/proto/advisorpb"
+	"weatherservices/shared/proto/weatherpb"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	geocodeCacheTTL = 24 * time.Hour
+	weatherCacheTTL = 15 * time.Minute
+
+	// maxInMemoryCacheEntries bounds the default backend's size; once full,
+	// the least recently used entry is evicted to make room.
+	maxInMemoryCacheEntries = 10_000
+)
+
+var (
+	cacheHits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_cache_hits_total",
+			Help: "Total cache hits, by kind",
+		},
+		[]string{"kind"},
+	)
+	cacheMisses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_cache_misses_total",
+			Help: "Total cache misses, by kind",
+		},
+		[]string{"kind"},
+	)
+)
+
+// Cache is the seam advisorService caches geocoding and current-weather
+// lookups through. Values are opaque strings so the same interface works
+// whether the backend is in-process memory or an external store like Redis.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, bool)
+	Set(ctx context.Context, key string, value string, ttl time.Duration)
+}
+
+// normalizedCityKey builds the geocode cache key, normalizing case and
+// whitespace so "Paris", " paris", and "PARIS" all share a cache entry.
+func normalizedCityKey(location, language string) string {
+	return fmt.Sprintf("geocode:%s:%s", strings.ToLower(strings.TrimSpace(location)), language)
+}
+
+// roundedCoordKey builds the weather cache key, rounding to ~1.1km so nearby
+// requests for the same city share an entry instead of missing on float
+// jitter. provider and units are folded in too: NWS/OWM/Open-Meteo disagree
+// on readings for the same coordinates, and each provider reports its own
+// Units, so a key on coordinates alone would let a provider or unit-system
+// switch serve stale data from the previous configuration.
+func roundedCoordKey(provider, units string, lat, lon float64) string {
+	return fmt.Sprintf("weather:%s:%s:%.2f,%.2f", provider, units, lat, lon)
+}
+
+// cachedGeocode is what we persist in the geocode cache: coordinates plus
+// the name of the geocoder that resolved them, so a cache hit can still
+// report provenance.
+type cachedGeocode struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	ResolvedBy string  `json:"resolved_by"`
+}
+
+func (s *advisorService) geocodeCityCached(ctx context.Context, city *advisorpb.CityData, language string) (float64, float64, string, error) {
+	if s.cache == nil {
+		return s.geocoder.Geocode(ctx, city.Location, language)
+	}
+
+	key := normalizedCityKey(city.Location, language)
+	if cached, ok := s.cache.Get(ctx, key); ok {
+		var entry cachedGeocode
+		if err := json.Unmarshal([]byte(cached), &entry); err == nil {
+			cacheHits.WithLabelValues("geocode").Inc()
+			return entry.Latitude, entry.Longitude, entry.ResolvedBy, nil
+		}
+	}
+	cacheMisses.WithLabelValues("geocode").Inc()
+
+	lat, lon, resolvedBy, err := s.geocoder.Geocode(ctx, city.Location, language)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if encoded, err := json.Marshal(cachedGeocode{Latitude: lat, Longitude: lon, ResolvedBy: resolvedBy}); err == nil {
+		s.cache.Set(ctx, key, string(encoded), geocodeCacheTTL)
+	}
+	return lat, lon, resolvedBy, nil
+}
+
+func (s *advisorService) currentWeatherCached(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error) {
+	if s.cache == nil {
+		return s.weatherProvider.CurrentByCoords(ctx, lat, lon)
+	}
+
+	key := roundedCoordKey(s.weatherProvider.Name(), s.weatherProvider.Units(), lat, lon)
+	if cached, ok := s.cache.Get(ctx, key); ok {
+		var resp weatherpb.WeatherResponse
+		if err := json.Unmarshal([]byte(cached), &resp); err == nil {
+			cacheHits.WithLabelValues("weather").Inc()
+			return &resp, nil
+		}
+	}
+	cacheMisses.WithLabelValues("weather").Inc()
+
+	resp, err := s.weatherProvider.CurrentByCoords(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if encoded, err := json.Marshal(resp); err == nil {
+		s.cache.Set(ctx, key, string(encoded), weatherCacheTTL)
+	}
+	return resp, nil
+}
+
+type inMemoryCacheEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// InMemoryCache is a size-bounded, TTL-aware LRU cache. It's the default
+// cache backend: no external dependency, good enough to absorb bursty
+// streaming load within Nominatim's 1 req/s policy.
+type InMemoryCache struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+func NewInMemoryCache(maxSize int) *InMemoryCache {
+	if maxSize <= 0 {
+		maxSize = maxInMemoryCacheEntries
+	}
+	return &InMemoryCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *InMemoryCache) Get(_ context.Context, key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*inMemoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *InMemoryCache) Set(_ context.Context, key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*inMemoryCacheEntry).value = value
+		elem.Value.(*inMemoryCacheEntry).expiresAt = time.Now().Add(ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&inMemoryCacheEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	c.entries[key] = elem
+
+	if c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*inMemoryCacheEntry).key)
+		}
+	}
+}
+
+// RedisCache is the optional cache backend for deployments that want
+// cache state shared across advisor replicas.
+type RedisCache struct {
+	client *redis.Client
+}
+
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{client: redis.NewClient(&redis.Options{Addr: addr})}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool) {
+	value, err := c.client.Get(ctx, key).Result()
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func (c *RedisCache) Set(ctx context.Context, key, value string, ttl time.Duration) {
+	c.client.Set(ctx, key, value, ttl)
+}
@@ -0,0 +1,227 @@
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Geocoder resolves a free-text location into coordinates. Name identifies
+// the geocoder in logs/metrics and is surfaced to callers so they can see
+// which provider actually resolved a given city.
+type Geocoder interface {
+	Name() string
+	Geocode(ctx context.Context, location, language string) (lat, lon float64, err error)
+}
+
+// tokenBucketLimiter is a minimal blocking rate limiter: Wait returns once a
+// token is available, refilling one token every `interval`. It exists so
+// NominatimGeocoder doesn't need an external rate-limiting dependency for a
+// single rule ("no more than 1 req/s").
+type tokenBucketLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucketLimiter(interval time.Duration) *tokenBucketLimiter {
+	return &tokenBucketLimiter{interval: interval}
+}
+
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	wait := time.Until(l.last.Add(l.interval))
+	if wait <= 0 {
+		l.last = time.Now()
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		l.last = time.Now()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NominatimGeocoder queries OpenStreetMap's Nominatim service, observing its
+// usage policy: a descriptive User-Agent carrying a contact email, and at
+// most one request per second.
+type NominatimGeocoder struct {
+	httpClient *http.Client
+	userAgent  string
+	limiter    *tokenBucketLimiter
+}
+
+func NewNominatimGeocoder(contactEmail string) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		userAgent:  fmt.Sprintf("weather-service-gemini (%s)", contactEmail),
+		limiter:    newTokenBucketLimiter(time.Second),
+	}
+}
+
+func (g *NominatimGeocoder) Name() string { return "nominatim" }
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, location, language string) (float64, float64, error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return 0, 0, fmt.Errorf("nominatim rate limit wait: %v", err)
+	}
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	reqURL := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1&accept-language=%s",
+		url.QueryEscape(location), language)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("geocoding failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("geocoding failed: %s", resp.Status)
+	}
+
+	var results []struct {
+		Latitude  string `json:"lat"`
+		Longitude string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, fmt.Errorf("JSON decoding failed: %v", err)
+	}
+	if len(results) == 0 {
+		return 0, 0, fmt.Errorf("no results found for city: %s", location)
+	}
+
+	var lat, lon float64
+	if _, err := fmt.Sscanf(results[0].Latitude, "%f", &lat); err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude from nominatim: %v", err)
+	}
+	if _, err := fmt.Sscanf(results[0].Longitude, "%f", &lon); err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude from nominatim: %v", err)
+	}
+	return lat, lon, nil
+}
+
+// geoCodeResponse matches Open-Meteo's /v1/search response shape.
+type geoCodeResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+		Admin1    string  `json:"admin1"`
+	} `json:"results"`
+}
+
+// OpenMeteoGeocoder queries Open-Meteo's free, keyless geocoding search
+// endpoint. It's a good second-choice geocoder: same results shape we
+// already model, no usage policy to violate.
+type OpenMeteoGeocoder struct {
+	httpClient *http.Client
+}
+
+func NewOpenMeteoGeocoder() *OpenMeteoGeocoder {
+	return &OpenMeteoGeocoder{httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (g *OpenMeteoGeocoder) Name() string { return "open-meteo" }
+
+func (g *OpenMeteoGeocoder) Geocode(ctx context.Context, location, language string) (float64, float64, error) {
+	if language == "" {
+		language = defaultLanguage
+	}
+	reqURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1&language=%s",
+		url.QueryEscape(location), language)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("geocoding failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("geocoding failed: %s", resp.Status)
+	}
+
+	var geoResponse geoCodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geoResponse); err != nil {
+		return 0, 0, fmt.Errorf("JSON decoding failed: %v", err)
+	}
+	if len(geoResponse.Results) == 0 {
+		return 0, 0, fmt.Errorf("no results found for city: %s", location)
+	}
+	return geoResponse.Results[0].Latitude, geoResponse.Results[0].Longitude, nil
+}
+
+// StaticGeocoder resolves against a fixed set of known city coordinates,
+// e.g. the availableCities map the CLI ships with. It's the last resort in
+// a geocoder chain: no network call, so it never fails for an unknown
+// reason, only for an unlisted city.
+type StaticGeocoder struct {
+	cities map[string][2]float64
+}
+
+func NewStaticGeocoder(cities map[string][2]float64) *StaticGeocoder {
+	return &StaticGeocoder{cities: cities}
+}
+
+func (g *StaticGeocoder) Name() string { return "static" }
+
+func (g *StaticGeocoder) Geocode(_ context.Context, location, _ string) (float64, float64, error) {
+	for name, coords := range g.cities {
+		if strings.EqualFold(name, location) {
+			return coords[0], coords[1], nil
+		}
+	}
+	return 0, 0, fmt.Errorf("no static coordinates for city: %s", location)
+}
+
+// ChainGeocoder tries each geocoder in order, applying a per-provider
+// timeout, and returns the first success along with the name of the
+// geocoder that resolved it.
+type ChainGeocoder struct {
+	geocoders []Geocoder
+	timeout   time.Duration
+}
+
+func NewChainGeocoder(timeout time.Duration, geocoders ...Geocoder) *ChainGeocoder {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &ChainGeocoder{geocoders: geocoders, timeout: timeout}
+}
+
+func (g *ChainGeocoder) Name() string { return "chain" }
+
+func (g *ChainGeocoder) Geocode(ctx context.Context, location, language string) (lat, lon float64, resolvedBy string, err error) {
+	var lastErr error
+	for _, geocoder := range g.geocoders {
+		attemptCtx, cancel := context.WithTimeout(ctx, g.timeout)
+		lat, lon, err := geocoder.Geocode(attemptCtx, location, language)
+		cancel()
+		if err == nil {
+			return lat, lon, geocoder.Name(), nil
+		}
+		lastErr = fmt.Errorf("%s: %v", geocoder.Name(), err)
+	}
+	return 0, 0, "", fmt.Errorf("all geocoders failed for %s: %v", location, lastErr)
+}
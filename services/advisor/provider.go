@@ -0,0 +1,671 @@
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"weatherservices/shared/proto/weatherpb"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var weatherProviderRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_provider_requests_total",
+		Help: "Total weather provider requests, broken down per provider",
+	},
+	[]string{"provider", "status"},
+)
+
+// WeatherProvider is the seam between advisorService and whichever weather
+// backend actually answers a request. Concrete providers only need to know
+// how to translate lat/lon into the shared weatherpb types; selecting which
+// provider to use for a given coordinate is MultiProvider's job.
+type WeatherProvider interface {
+	Name() string
+	// Units reports the unit system values returned by CurrentByCoords and
+	// ForecastByCoords are expressed in, e.g. "metric" or "imperial". Callers
+	// that cache responses need this alongside Name to key on, since a
+	// provider's configured units can change what a given coordinate's cached
+	// entry actually means.
+	Units() string
+	SupportsRegion(lat, lon float64) bool
+	CurrentByCoords(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error)
+	ForecastByCoords(ctx context.Context, lat, lon float64, days int32) (*weatherpb.ForecastResponse, error)
+}
+
+// AlertsProvider is implemented by providers that can surface active
+// severe-weather alerts. Not every backend has an alerts feed (Open-Meteo
+// doesn't), so this is kept separate from WeatherProvider and probed with a
+// type assertion.
+type AlertsProvider interface {
+	AlertsByCoords(ctx context.Context, lat, lon float64) (*weatherpb.AlertsResponse, error)
+}
+
+func recordProviderRequest(provider, status string) {
+	weatherProviderRequests.WithLabelValues(provider, status).Inc()
+}
+
+// openMeteoProvider talks to the free, keyless Open-Meteo API. It's the
+// default fallback for coordinates outside the US.
+type openMeteoProvider struct {
+	httpClient *http.Client
+}
+
+func NewOpenMeteoProvider() *openMeteoProvider {
+	return &openMeteoProvider{httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *openMeteoProvider) Name() string { return "open-meteo" }
+
+// Units reports the units open-meteo.com returns by default: no units
+// param is sent, and its API defaults to Celsius and m/s.
+func (p *openMeteoProvider) Units() string { return "metric" }
+
+func (p *openMeteoProvider) SupportsRegion(_, _ float64) bool { return true }
+
+func (p *openMeteoProvider) CurrentByCoords(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error) {
+	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current_weather=true&current=relative_humidity_2m", lat, lon)
+	var payload struct {
+		CurrentWeather struct {
+			Temperature float64 `json:"temperature"`
+			WindSpeed   float64 `json:"windspeed"`
+			WeatherCode int     `json:"weathercode"`
+		} `json:"current_weather"`
+		Current struct {
+			RelativeHumidity2m int32 `json:"relative_humidity_2m"`
+		} `json:"current"`
+	}
+	err := p.get(ctx, url, &payload)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+	return &weatherpb.WeatherResponse{
+		Temperature: payload.CurrentWeather.Temperature,
+		Humidity:    payload.Current.RelativeHumidity2m,
+		WindSpeed:   payload.CurrentWeather.WindSpeed,
+		Description: weatherCodeToDescription(payload.CurrentWeather.WeatherCode),
+	}, nil
+}
+
+func (p *openMeteoProvider) ForecastByCoords(ctx context.Context, lat, lon float64, days int32) (*weatherpb.ForecastResponse, error) {
+	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&daily=temperature_2m_max,temperature_2m_min,precipitation_probability_max,windgusts_10m_max&forecast_days=%d&timezone=auto", lat, lon, days)
+	var payload struct {
+		Daily struct {
+			Time              []string  `json:"time"`
+			TempMax           []float64 `json:"temperature_2m_max"`
+			TempMin           []float64 `json:"temperature_2m_min"`
+			PrecipProbability []int32   `json:"precipitation_probability_max"`
+			WindGustsMax      []float64 `json:"windgusts_10m_max"`
+		} `json:"daily"`
+	}
+	err := p.get(ctx, url, &payload)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+
+	// Open-Meteo can return a degraded response where the daily arrays are
+	// shorter than Time (or missing); only trust indices every array has.
+	count := len(payload.Daily.Time)
+	for _, lengths := range [][]float64{payload.Daily.TempMax, payload.Daily.TempMin, payload.Daily.WindGustsMax} {
+		if len(lengths) < count {
+			count = len(lengths)
+		}
+	}
+	if len(payload.Daily.PrecipProbability) < count {
+		count = len(payload.Daily.PrecipProbability)
+	}
+
+	resp := &weatherpb.ForecastResponse{}
+	for i := 0; i < count; i++ {
+		resp.Daily = append(resp.Daily, &weatherpb.DailyForecast{
+			Date:              payload.Daily.Time[i],
+			TempMax:           payload.Daily.TempMax[i],
+			TempMin:           payload.Daily.TempMin[i],
+			PrecipProbability: payload.Daily.PrecipProbability[i],
+			WindGust:          payload.Daily.WindGustsMax[i],
+		})
+	}
+	return resp, nil
+}
+
+func (p *openMeteoProvider) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("open-meteo request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("open-meteo request failed: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// weatherCodeToDescription maps Open-Meteo's WMO weather codes to a short
+// human-readable condition string.
+func weatherCodeToDescription(code int) string {
+	switch {
+	case code == 0:
+		return "clear sky"
+	case code <= 3:
+		return "partly cloudy"
+	case code <= 48:
+		return "fog"
+	case code <= 67:
+		return "rain"
+	case code <= 77:
+		return "snow"
+	case code <= 99:
+		return "thunderstorm"
+	default:
+		return "unknown"
+	}
+}
+
+// OpenWeatherMapConfig carries the config keys OWM's API expects, mirroring
+// the app_id/units/lang options exposed by their own integrations.
+type OpenWeatherMapConfig struct {
+	AppID string
+	Units string // "standard", "metric", or "imperial"
+	Lang  string // ISO 639-1, e.g. "en"
+}
+
+type openWeatherMapProvider struct {
+	cfg        OpenWeatherMapConfig
+	httpClient *http.Client
+}
+
+func NewOpenWeatherMapProvider(cfg OpenWeatherMapConfig) *openWeatherMapProvider {
+	if cfg.Units == "" {
+		cfg.Units = "metric"
+	}
+	if cfg.Lang == "" {
+		cfg.Lang = "en"
+	}
+	return &openWeatherMapProvider{cfg: cfg, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *openWeatherMapProvider) Name() string { return "openweathermap" }
+
+// Units reports "metric": CurrentByCoords/ForecastByCoords convert OWM's
+// cfg.Units-dependent readings (°F/mph for "imperial", Kelvin for
+// "standard") to Celsius/m/s before returning, same contract every other
+// provider gives callers.
+func (p *openWeatherMapProvider) Units() string { return "metric" }
+
+// owmTempToCelsius converts a temperature reading in OWM's configured units
+// to Celsius.
+func owmTempToCelsius(temp float64, units string) float64 {
+	switch units {
+	case "imperial":
+		return (temp - 32) * 5 / 9
+	case "standard":
+		return temp - 273.15
+	default:
+		return temp
+	}
+}
+
+// owmSpeedToMS converts a wind-speed reading in OWM's configured units to
+// m/s. OWM already reports m/s for both "metric" and "standard"; only
+// "imperial" (mph) needs converting.
+func owmSpeedToMS(speed float64, units string) float64 {
+	if units == "imperial" {
+		return speed * 0.44704
+	}
+	return speed
+}
+
+func (p *openWeatherMapProvider) SupportsRegion(_, _ float64) bool { return true }
+
+func (p *openWeatherMapProvider) CurrentByCoords(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error) {
+	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=%s&lang=%s",
+		lat, lon, p.cfg.AppID, p.cfg.Units, p.cfg.Lang)
+	var payload struct {
+		Main struct {
+			Temp     float64 `json:"temp"`
+			Humidity int32   `json:"humidity"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+	}
+	err := p.get(ctx, url, &payload)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+	description := "unknown"
+	if len(payload.Weather) > 0 {
+		description = payload.Weather[0].Description
+	}
+	return &weatherpb.WeatherResponse{
+		Temperature: owmTempToCelsius(payload.Main.Temp, p.cfg.Units),
+		Humidity:    payload.Main.Humidity,
+		WindSpeed:   owmSpeedToMS(payload.Wind.Speed, p.cfg.Units),
+		Description: description,
+	}, nil
+}
+
+// owmDayAccumulator folds the free 5-day/3-hour forecast's 3-hourly
+// entries into one calendar-day summary.
+type owmDayAccumulator struct {
+	tempMax, tempMin float64
+	hasTemp          bool
+	pop              float64
+	gust             float64
+}
+
+// ForecastByCoords uses OWM's free 5-day/3-hour forecast endpoint rather
+// than /forecast/daily, which requires a paid plan. Its 3-hourly entries
+// are bucketed by calendar date, and unlike the daily endpoint each entry
+// carries a real wind.gust field instead of just a mean speed.
+func (p *openWeatherMapProvider) ForecastByCoords(ctx context.Context, lat, lon float64, days int32) (*weatherpb.ForecastResponse, error) {
+	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/forecast?lat=%f&lon=%f&appid=%s&units=%s&lang=%s",
+		lat, lon, p.cfg.AppID, p.cfg.Units, p.cfg.Lang)
+	var payload struct {
+		List []struct {
+			DtTxt string `json:"dt_txt"`
+			Main  struct {
+				TempMax float64 `json:"temp_max"`
+				TempMin float64 `json:"temp_min"`
+			} `json:"main"`
+			Pop  float64 `json:"pop"`
+			Wind struct {
+				Gust float64 `json:"gust"`
+			} `json:"wind"`
+		} `json:"list"`
+	}
+	err := p.get(ctx, url, &payload)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []string
+	byDate := make(map[string]*owmDayAccumulator)
+	for _, entry := range payload.List {
+		if len(entry.DtTxt) < 10 {
+			continue
+		}
+		tempMax := owmTempToCelsius(entry.Main.TempMax, p.cfg.Units)
+		tempMin := owmTempToCelsius(entry.Main.TempMin, p.cfg.Units)
+		gust := owmSpeedToMS(entry.Wind.Gust, p.cfg.Units)
+
+		date := entry.DtTxt[:10]
+		accum, ok := byDate[date]
+		if !ok {
+			accum = &owmDayAccumulator{}
+			byDate[date] = accum
+			dates = append(dates, date)
+		}
+		if !accum.hasTemp || tempMax > accum.tempMax {
+			accum.tempMax = tempMax
+		}
+		if !accum.hasTemp || tempMin < accum.tempMin {
+			accum.tempMin = tempMin
+		}
+		accum.hasTemp = true
+		if entry.Pop > accum.pop {
+			accum.pop = entry.Pop
+		}
+		if gust > accum.gust {
+			accum.gust = gust
+		}
+	}
+
+	resp := &weatherpb.ForecastResponse{}
+	for i, date := range dates {
+		if int32(i) >= days {
+			break
+		}
+		accum := byDate[date]
+		resp.Daily = append(resp.Daily, &weatherpb.DailyForecast{
+			Date:              date,
+			TempMax:           accum.tempMax,
+			TempMin:           accum.tempMin,
+			PrecipProbability: int32(accum.pop * 100),
+			WindGust:          accum.gust,
+		})
+	}
+	return resp, nil
+}
+
+func (p *openWeatherMapProvider) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("openweathermap request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("openweathermap request failed: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// nwsProvider talks to the US National Weather Service API. It only covers
+// US territory, hence SupportsRegion's bounding-box check, and is the only
+// backend here with an alerts feed (MultiProvider's AlertsByCoords forwards
+// to it for US coordinates).
+type nwsProvider struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+func NewNWSProvider(contactEmail string) *nwsProvider {
+	return &nwsProvider{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		userAgent:  fmt.Sprintf("weather-service-gemini (%s)", contactEmail),
+	}
+}
+
+func (p *nwsProvider) Name() string { return "nws" }
+
+// Units reports "metric": forecastPeriods requests ?units=si (°C, km/h)
+// and converts wind speed from km/h to m/s before returning.
+func (p *nwsProvider) Units() string { return "metric" }
+
+func (p *nwsProvider) SupportsRegion(lat, lon float64) bool {
+	return lat >= 24.0 && lat <= 50.0 && lon >= -125.0 && lon <= -66.0
+}
+
+// gridpoint resolves a lat/lon to the office/grid cell NWS expects, the
+// first step of their documented points -> gridpoints flow.
+func (p *nwsProvider) gridpoint(ctx context.Context, lat, lon float64) (office string, x, y int, err error) {
+	url := fmt.Sprintf("https://api.weather.gov/points/%f,%f", lat, lon)
+	var payload struct {
+		Properties struct {
+			GridID string `json:"gridId"`
+			GridX  int    `json:"gridX"`
+			GridY  int    `json:"gridY"`
+		} `json:"properties"`
+	}
+	if err := p.get(ctx, url, &payload); err != nil {
+		return "", 0, 0, err
+	}
+	return payload.Properties.GridID, payload.Properties.GridX, payload.Properties.GridY, nil
+}
+
+func (p *nwsProvider) CurrentByCoords(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error) {
+	office, x, y, err := p.gridpoint(ctx, lat, lon)
+	if err != nil {
+		recordProviderRequest(p.Name(), statusFor(err))
+		return nil, err
+	}
+	periods, err := p.forecastPeriods(ctx, office, x, y)
+	if err == nil && len(periods) == 0 {
+		err = fmt.Errorf("nws: no forecast periods returned")
+	}
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+	current := periods[0]
+	return &weatherpb.WeatherResponse{
+		Temperature: current.TemperatureC,
+		Humidity:    current.RelativeHumidity,
+		Description: current.ShortForecast,
+		WindSpeed:   current.WindSpeedMS,
+	}, nil
+}
+
+// nwsDayAccumulator folds a date's daytime/nighttime periods into the
+// single calendar-day entry weatherpb.DailyForecast expects.
+type nwsDayAccumulator struct {
+	date              string
+	high, low         float64
+	hasHigh, hasLow   bool
+	precipProbability int32
+	windGust          float64
+}
+
+func (p *nwsProvider) ForecastByCoords(ctx context.Context, lat, lon float64, days int32) (*weatherpb.ForecastResponse, error) {
+	office, x, y, err := p.gridpoint(ctx, lat, lon)
+	if err != nil {
+		recordProviderRequest(p.Name(), statusFor(err))
+		return nil, err
+	}
+	periods, err := p.forecastPeriods(ctx, office, x, y)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+
+	// NWS periods are half-day (day/night), not full days, so pair each
+	// period up by its calendar date before turning it into a DailyForecast.
+	var dates []string
+	byDate := make(map[string]*nwsDayAccumulator)
+	for _, period := range periods {
+		if len(period.StartTime) < 10 {
+			continue
+		}
+		date := period.StartTime[:10]
+		accum, ok := byDate[date]
+		if !ok {
+			accum = &nwsDayAccumulator{date: date}
+			byDate[date] = accum
+			dates = append(dates, date)
+		}
+		if period.IsDaytime {
+			accum.high = period.TemperatureC
+			accum.hasHigh = true
+		} else {
+			accum.low = period.TemperatureC
+			accum.hasLow = true
+		}
+		if period.PrecipProbability > accum.precipProbability {
+			accum.precipProbability = period.PrecipProbability
+		}
+		if period.WindSpeedMS > accum.windGust {
+			accum.windGust = period.WindSpeedMS
+		}
+	}
+
+	resp := &weatherpb.ForecastResponse{}
+	for i, date := range dates {
+		if int32(i) >= days {
+			break
+		}
+		accum := byDate[date]
+		high, low := accum.high, accum.low
+		switch {
+		case !accum.hasHigh:
+			high = low
+		case !accum.hasLow:
+			low = high
+		}
+		resp.Daily = append(resp.Daily, &weatherpb.DailyForecast{
+			Date:              date,
+			TempMax:           high,
+			TempMin:           low,
+			PrecipProbability: accum.precipProbability,
+			WindGust:          accum.windGust,
+		})
+	}
+	return resp, nil
+}
+
+func (p *nwsProvider) AlertsByCoords(ctx context.Context, lat, lon float64) (*weatherpb.AlertsResponse, error) {
+	url := fmt.Sprintf("https://api.weather.gov/alerts/active?point=%f,%f", lat, lon)
+	var payload struct {
+		Features []struct {
+			Properties struct {
+				Event    string `json:"event"`
+				Severity string `json:"severity"`
+				Headline string `json:"headline"`
+				Expires  string `json:"expires"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	err := p.get(ctx, url, &payload)
+	recordProviderRequest(p.Name(), statusFor(err))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &weatherpb.AlertsResponse{}
+	for _, feature := range payload.Features {
+		resp.Alerts = append(resp.Alerts, &weatherpb.WeatherAlert{
+			Event:    feature.Properties.Event,
+			Severity: feature.Properties.Severity,
+			Headline: feature.Properties.Headline,
+			Expires:  feature.Properties.Expires,
+		})
+	}
+	return resp, nil
+}
+
+type nwsForecastPeriod struct {
+	StartTime         string
+	IsDaytime         bool
+	TemperatureC      float64
+	ShortForecast     string
+	WindSpeedMS       float64
+	PrecipProbability int32
+	RelativeHumidity  int32
+}
+
+// forecastPeriods fetches NWS's half-day (day/night) forecast periods,
+// requesting SI units so callers don't need to know NWS defaults to °F and
+// mph.
+func (p *nwsProvider) forecastPeriods(ctx context.Context, office string, x, y int) ([]nwsForecastPeriod, error) {
+	url := fmt.Sprintf("https://api.weather.gov/gridpoints/%s/%d,%d/forecast?units=si", office, x, y)
+	var payload struct {
+		Properties struct {
+			Periods []struct {
+				StartTime                  string  `json:"startTime"`
+				IsDaytime                  bool    `json:"isDaytime"`
+				Temperature                float64 `json:"temperature"`
+				ShortForecast              string  `json:"shortForecast"`
+				WindSpeed                  string  `json:"windSpeed"`
+				ProbabilityOfPrecipitation struct {
+					Value *float64 `json:"value"`
+				} `json:"probabilityOfPrecipitation"`
+				RelativeHumidity struct {
+					Value *float64 `json:"value"`
+				} `json:"relativeHumidity"`
+			} `json:"periods"`
+		} `json:"properties"`
+	}
+	if err := p.get(ctx, url, &payload); err != nil {
+		return nil, err
+	}
+
+	periods := make([]nwsForecastPeriod, 0, len(payload.Properties.Periods))
+	for _, period := range payload.Properties.Periods {
+		// With units=si, windSpeed comes back as e.g. "10 km/h" or
+		// "10 to 15 km/h"; take the leading figure and convert to m/s.
+		var windSpeedKmh float64
+		fmt.Sscanf(period.WindSpeed, "%f", &windSpeedKmh)
+
+		var precip int32
+		if period.ProbabilityOfPrecipitation.Value != nil {
+			precip = int32(*period.ProbabilityOfPrecipitation.Value)
+		}
+		var humidity int32
+		if period.RelativeHumidity.Value != nil {
+			humidity = int32(*period.RelativeHumidity.Value)
+		}
+
+		periods = append(periods, nwsForecastPeriod{
+			StartTime:         period.StartTime,
+			IsDaytime:         period.IsDaytime,
+			TemperatureC:      period.Temperature,
+			ShortForecast:     period.ShortForecast,
+			WindSpeedMS:       windSpeedKmh / 3.6,
+			PrecipProbability: precip,
+			RelativeHumidity:  humidity,
+		})
+	}
+	return periods, nil
+}
+
+func (p *nwsProvider) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept", "application/geo+json")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("nws request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nws request failed: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// MultiProvider routes each coordinate to NWS when it falls inside the US
+// (more accurate, free, and alert-capable) and otherwise to the fallback
+// provider. It doesn't record advisor_provider_requests_total itself; the
+// chosen provider's own method does that, by name, whether it's reached
+// through MultiProvider or injected directly.
+type MultiProvider struct {
+	us       WeatherProvider
+	fallback WeatherProvider
+}
+
+func NewMultiProvider(us, fallback WeatherProvider) *MultiProvider {
+	return &MultiProvider{us: us, fallback: fallback}
+}
+
+func (m *MultiProvider) Name() string { return "multi" }
+
+// Units reports "mixed": the underlying provider is chosen per-coordinate
+// by selectProvider, so no single provider's Units applies. The providers
+// in this package all return metric values. SupportsRegion is deterministic
+// per coordinate, but a rounded cache bucket at the edge of the US
+// provider's region can hold coordinates routed to either provider.
+func (m *MultiProvider) Units() string { return "mixed" }
+
+func (m *MultiProvider) SupportsRegion(_, _ float64) bool { return true }
+
+func (m *MultiProvider) selectProvider(lat, lon float64) WeatherProvider {
+	if m.us != nil && m.us.SupportsRegion(lat, lon) {
+		return m.us
+	}
+	return m.fallback
+}
+
+func (m *MultiProvider) CurrentByCoords(ctx context.Context, lat, lon float64) (*weatherpb.WeatherResponse, error) {
+	return m.selectProvider(lat, lon).CurrentByCoords(ctx, lat, lon)
+}
+
+func (m *MultiProvider) ForecastByCoords(ctx context.Context, lat, lon float64, days int32) (*weatherpb.ForecastResponse, error) {
+	return m.selectProvider(lat, lon).ForecastByCoords(ctx, lat, lon, days)
+}
+
+func (m *MultiProvider) AlertsByCoords(ctx context.Context, lat, lon float64) (*weatherpb.AlertsResponse, error) {
+	provider := m.selectProvider(lat, lon)
+	alertsProvider, ok := provider.(AlertsProvider)
+	if !ok {
+		return &weatherpb.AlertsResponse{}, nil
+	}
+	return alertsProvider.AlertsByCoords(ctx, lat, lon)
+}
+
+func statusFor(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,119 @@
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"weatherservices/shared/proto/advisorpb"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// conditionEmoji maps a provider's free-text condition description to a
+// single representative emoji, the same rough classification wttr.in uses
+// for its compact panels.
+func conditionEmoji(description string) string {
+	d := strings.ToLower(description)
+	switch {
+	case strings.Contains(d, "thunder"):
+		return "⛈"
+	case strings.Contains(d, "snow"):
+		return "❄️"
+	case strings.Contains(d, "rain"), strings.Contains(d, "drizzle"), strings.Contains(d, "shower"):
+		return "🌧"
+	case strings.Contains(d, "cloud"), strings.Contains(d, "fog"), strings.Contains(d, "overcast"):
+		return "☁️"
+	default:
+		return "☀️"
+	}
+}
+
+// renderANSIPanel lays out a city's current conditions and a short
+// forecast as an aligned, wttr.in-style text panel suitable for `curl`.
+func renderANSIPanel(data *cityWeatherData, f *unitFormatter) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", conditionEmoji(data.Current.Description), data.Location)
+	fmt.Fprintf(&b, "  Temp: %-8s Wind: %-8s Humidity: %d%%\n",
+		f.formatTemperature(data.Current.Temperature), f.formatSpeed(data.Current.WindSpeed), data.Current.Humidity)
+
+	maxDays := 3
+	if len(data.Forecast) < maxDays {
+		maxDays = len(data.Forecast)
+	}
+	for i := 0; i < maxDays; i++ {
+		day := data.Forecast[i]
+		fmt.Fprintf(&b, "  %-12s low %-8s high %-8s precip %3d%%\n",
+			day.Date, f.formatTemperature(day.TempMin), f.formatTemperature(day.TempMax), day.PrecipProbability)
+	}
+	return b.String()
+}
+
+// streamANSIPanels emits one chunk per city, bypassing Gemini entirely, so
+// non-LLM consumers like `curl advisor/paris` get a cheap, instant render.
+func (s *advisorService) streamANSIPanels(weatherData []*cityWeatherData, units string, geocodersByCity map[string]string, stream advisorpb.AdvisorService_StreamAdviceServer) error {
+	f := newUnitFormatter(units)
+	for _, data := range weatherData {
+		if err := stream.Send(&advisorpb.StreamAdviceResponse{Chunk: renderANSIPanel(data, f), IsComplete: false}); err != nil {
+			return fmt.Errorf("failed to send chunk: %v", err)
+		}
+	}
+	return stream.Send(&advisorpb.StreamAdviceResponse{Chunk: "", IsComplete: true, ResolvedGeocoders: geocodersByCity})
+}
+
+// structuredAdvice is the schema Gemini is asked to fill in JSON_STRUCTURED
+// mode, giving dashboards and scripts a machine-parseable alternative to
+// prose advice.
+type structuredAdvice struct {
+	Summary    string   `json:"summary"`
+	Clothing   []string `json:"clothing"`
+	Activities []string `json:"activities"`
+	Warnings   []string `json:"warnings"`
+}
+
+// streamJSONStructured asks Gemini for advice constrained to the
+// structuredAdvice schema, validates the response server-side, and
+// re-emits the validated JSON as a single chunk.
+func (s *advisorService) streamJSONStructured(ctx context.Context, weatherData []*cityWeatherData, language, units string, geocodersByCity map[string]string, stream advisorpb.AdvisorService_StreamAdviceServer) error {
+	f := newUnitFormatter(units)
+	var sections strings.Builder
+	for _, data := range weatherData {
+		sections.WriteString(buildCitySection(data, f))
+	}
+
+	model := s.genaiClient.GenerativeModel("gemini-2.5-flash")
+	model.GenerationConfig.ResponseMIMEType = "application/json"
+	prompt := fmt.Sprintf(`%sWeather advisor. Based on this data, respond with JSON matching this exact shape: {"summary": string, "clothing": [string], "activities": [string], "warnings": [string]}.
+
+%s`, languageInstruction(language), sections.String())
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return fmt.Errorf("gemini API failed: %v", err)
+	}
+	if len(resp.Candidates) == 0 {
+		return fmt.Errorf("no response generated")
+	}
+
+	var raw strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if text, ok := part.(genai.Text); ok {
+			raw.WriteString(string(text))
+		}
+	}
+
+	var advice structuredAdvice
+	if err := json.Unmarshal([]byte(raw.String()), &advice); err != nil {
+		return fmt.Errorf("gemini returned invalid structured advice: %v", err)
+	}
+
+	validated, err := json.Marshal(advice)
+	if err != nil {
+		return fmt.Errorf("failed to re-encode structured advice: %v", err)
+	}
+
+	if err := stream.Send(&advisorpb.StreamAdviceResponse{Chunk: string(validated), IsComplete: false}); err != nil {
+		return fmt.Errorf("failed to send chunk: %v", err)
+	}
+	return stream.Send(&advisorpb.StreamAdviceResponse{Chunk: "", IsComplete: true, ResolvedGeocoders: geocodersByCity})
+}
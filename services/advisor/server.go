@@ -2,12 +2,8 @@ package advisor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
-	"time"
 	"weatherservices/shared/proto/advisorpb"
 	"weatherservices/shared/proto/weatherpb"
 
@@ -36,29 +32,23 @@ var (
 
 type advisorService struct {
 	advisorpb.UnimplementedAdvisorServiceServer
-	weatherSvc  *weatherpb.WeatherServiceServer
-	genaiClient *genai.Client
+	weatherProvider WeatherProvider
+	geocoder        *ChainGeocoder
+	genaiClient     *genai.Client
+	cache           Cache
 }
 
-type geoCodeResponse struct {
-	Results []struct {
-		Name      string  `json: "name"`
-		Latitude  float64 `json: "latitude"`
-		Longitude float64 `json: "longitude"`
-		Country   string  `json: "country"`
-		Admin1    string  `json: "admin1"`
-	} `json: "results"`
-}
-
-func NewAdvisorService(weatherSvc *weatherpb.WeatherServiceServer, geminiAPIkey string) (*advisorService, error) {
+func NewAdvisorService(weatherProvider WeatherProvider, geocoder *ChainGeocoder, geminiAPIkey string, cache Cache) (*advisorService, error) {
 	ctx := context.Background()
 	genaiClient, err := genai.NewClient(ctx, option.WithAPIKey(geminiAPIkey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini client: %v", err)
 	}
 	return &advisorService{
-		weatherSvc:  weatherSvc,
-		genaiClient: genaiClient,
+		weatherProvider: weatherProvider,
+		geocoder:        geocoder,
+		genaiClient:     genaiClient,
+		cache:           cache,
 	}, nil
 }
 
@@ -68,52 +58,114 @@ func (s *advisorService) Close() {
 	}
 }
 
-func (s *advisorService) geocodeCity(_ context.Context, city *advisorpb.CityData) (float64, float64, error) {
-	encodedQuery := url.QueryEscape(city.Location)
-	url := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1", encodedQuery)
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+const defaultForecastDays = 3
+
+// cityWeatherData bundles everything fetched for a single city so the
+// prompt builder can lay out current conditions, a multi-day forecast,
+// and any active alerts as distinct sections instead of one flat line.
+// Current/Forecast values stay in the provider's native metric units so
+// unit conversion only ever happens once, at render time.
+type cityWeatherData struct {
+	Location string
+	Current  *weatherpb.WeatherResponse
+	Forecast []*weatherpb.DailyForecast
+	Alerts   []*weatherpb.WeatherAlert
+	// ResolvedBy is the name of the geocoder that resolved Location, e.g.
+	// "nominatim" or "static", surfaced back to callers in stream metadata.
+	ResolvedBy string
+}
+
+// defaultLanguage is used when the caller doesn't request one; it matches
+// Nominatim's own default when accept-language is omitted.
+const defaultLanguage = "en"
+
+// fetchCityData geocodes the city and gathers current conditions, a
+// multi-day forecast, and any active severe-weather alerts for it.
+func (s *advisorService) fetchCityData(ctx context.Context, city *advisorpb.CityData, forecastDays int32, language string) (*cityWeatherData, error) {
+	latitude, longitude, resolvedBy, err := s.geocodeCityCached(ctx, city, language)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding failed for %s: %v", city.Location, err)
+	}
+
+	weatherResp, err := s.currentWeatherCached(ctx, latitude, longitude)
+	if err != nil {
+		return nil, fmt.Errorf("weather request failed for %s: %v", city.Location, err)
+	}
+
+	forecastResp, err := s.weatherProvider.ForecastByCoords(ctx, latitude, longitude, forecastDays)
 	if err != nil {
-		return 0, 0, fmt.Errorf("geocoding failed: %v", err)
+		return nil, fmt.Errorf("forecast request failed for %s: %v", city.Location, err)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("geocoding failed: %s", resp.Status)
+
+	// Alerts are an enrichment, not a requirement: a flaky alerts feed
+	// shouldn't take down otherwise-good current conditions and forecast.
+	var alerts []*weatherpb.WeatherAlert
+	if alertsProvider, ok := s.weatherProvider.(AlertsProvider); ok {
+		if alertsResp, err := alertsProvider.AlertsByCoords(ctx, latitude, longitude); err == nil {
+			alerts = alertsResp.Alerts
+		}
 	}
-	var geoResponse geoCodeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&geoResponse); err != nil {
-		return 0, 0, fmt.Errorf("JSON decoding failed: %v", err)
+
+	return &cityWeatherData{
+		Location:   city.Location,
+		Current:    weatherResp,
+		Forecast:   forecastResp.Daily,
+		Alerts:     alerts,
+		ResolvedBy: resolvedBy,
+	}, nil
+}
+
+// buildCitySection renders a city's current conditions, per-day forecast,
+// and warnings as distinct sections so the model doesn't have to infer
+// alerts on its own. Temperatures and wind speeds are rendered in the
+// requested unit system via f.
+func buildCitySection(data *cityWeatherData, f *unitFormatter) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "City: %s\nCurrent: Temp: %s, Condition: %s, Humidity: %d%%, Wind: %s\n",
+		data.Location, f.formatTemperature(data.Current.Temperature), data.Current.Description,
+		data.Current.Humidity, f.formatSpeed(data.Current.WindSpeed))
+
+	if len(data.Forecast) > 0 {
+		b.WriteString("Forecast:\n")
+		for _, day := range data.Forecast {
+			fmt.Fprintf(&b, "  %s: low %s / high %s, precip %d%%, gusts %s\n",
+				day.Date, f.formatTemperature(day.TempMin), f.formatTemperature(day.TempMax),
+				day.PrecipProbability, f.formatSpeed(day.WindGust))
+		}
 	}
-	if len(geoResponse.Results) == 0 {
-		return 0, 0, fmt.Errorf("no results found for city: %s", city.Location)
+
+	if len(data.Alerts) > 0 {
+		b.WriteString("Warnings:\n")
+		for _, alert := range data.Alerts {
+			fmt.Fprintf(&b, "  [%s/%s] %s (expires %s)\n", alert.Event, alert.Severity, alert.Headline, alert.Expires)
+		}
+	} else {
+		b.WriteString("Warnings: none active\n")
 	}
-	return geoResponse.Results[0].Latitude, geoResponse.Results[0].Longitude, nil
+
+	return b.String()
 }
 
 func (s *advisorService) getAdvice(ctx context.Context, advisorRequest *advisorpb.AdvisorRequest) (*advisorpb.AdvisorResponse, error) {
 	timer := prometheus.NewTimer(advisorDuration)
 	defer timer.ObserveDuration()
 
-	var weatherData []string
-	for _, city := range advisorRequest.Cities {
-		latitude, longitude, err := s.geocodeCity(ctx, city)
-		if err != nil {
-			advisorRequests.WithLabelValues("error").Inc()
-			return nil, fmt.Errorf("geocoding failed for %s: %v", city.Location, err)
-		}
+	forecastDays := advisorRequest.ForecastDays
+	if forecastDays <= 0 {
+		forecastDays = defaultForecastDays
+	}
 
-		weatherReq := &weatherpb.WeatherRequest{Latitude: latitude, Longitude: longitude}
-		weatherResp, err := (*s.weatherSvc).GetCurrentWeather(ctx, weatherReq)
+	var weatherData []*cityWeatherData
+	for _, city := range advisorRequest.Cities {
+		data, err := s.fetchCityData(ctx, city, forecastDays, advisorRequest.Language)
 		if err != nil {
 			advisorRequests.WithLabelValues("error").Inc()
-			return nil, fmt.Errorf("weather request failed for %s: %v", city.Location, err)
+			return nil, err
 		}
-		weatherInfo := fmt.Sprintf("City: %s, Temp: %.1f°C, Condition: %s, Humidity: %d%%, Wind: %.1f m/s",
-			city.Location, weatherResp.Temperature, weatherResp.Description, weatherResp.Humidity, weatherResp.WindSpeed)
-		weatherData = append(weatherData, weatherInfo)
+		weatherData = append(weatherData, data)
 	}
 
-	advice, err := s.generateAdvice(ctx, weatherData)
+	advice, err := s.generateAdvice(ctx, weatherData, advisorRequest.Language, advisorRequest.Units)
 	if err != nil {
 		advisorRequests.WithLabelValues("error").Inc()
 		return nil, fmt.Errorf("advice generation failed: %v", err)
@@ -122,9 +174,18 @@ func (s *advisorService) getAdvice(ctx context.Context, advisorRequest *advisorp
 	return &advisorpb.AdvisorResponse{Advice: advice}, nil
 }
 
-func (s *advisorService) generateAdvice(ctx context.Context, weatherData []string) (string, error) {
+func (s *advisorService) generateAdvice(ctx context.Context, weatherData []*cityWeatherData, language, units string) (string, error) {
+	f := newUnitFormatter(units)
+	var sections strings.Builder
+	for _, data := range weatherData {
+		sections.WriteString(buildCitySection(data, f))
+	}
+
 	model := s.genaiClient.GenerativeModel("gemini-2.5-flash")
-	prompt := fmt.Sprintf(`Weather advisor. Based on this data provide practical advice: %s Include: summary, clothing advice, activity suggestions, places to visit if good weather, warnings. Keep it concise.`, strings.Join(weatherData, "\n"))
+	prompt := fmt.Sprintf(`%sWeather advisor. Based on this data provide practical advice:
+
+%s
+Include: summary, clothing advice, activity suggestions, places to visit if good weather, warnings. Use the Warnings sections verbatim rather than guessing at alerts. Keep it concise.`, languageInstruction(language), sections.String())
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
 		return "", fmt.Errorf("gemini API failed: %v", err)
@@ -148,24 +209,21 @@ func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advi
 	timer := prometheus.NewTimer(advisorDuration)
 	defer timer.ObserveDuration()
 
-	var weatherData, failedCities []string
+	forecastDays := req.ForecastDays
+	if forecastDays <= 0 {
+		forecastDays = defaultForecastDays
+	}
+
+	var weatherData []*cityWeatherData
+	var failedCities []string
 
 	for _, city := range req.Cities {
-		latitude, longitude, err := s.geocodeCity(stream.Context(), city)
+		data, err := s.fetchCityData(stream.Context(), city, forecastDays, req.Language)
 		if err != nil {
 			failedCities = append(failedCities, city.Location)
 			continue
 		}
-		weatherReq := &weatherpb.WeatherRequest{Latitude: latitude, Longitude: longitude}
-		weatherResp, err := (*s.weatherSvc).GetCurrentWeather(stream.Context(), weatherReq)
-		if err != nil {
-			failedCities = append(failedCities, fmt.Sprintf("%s (weather failed)", city.Location))
-			continue
-		}
-
-		weatherInfo := fmt.Sprintf("City: %s, Temp: %.1f°C, Condition: %s, Humidity: %d%%, Wind: %.1f m/s",
-			city.Location, weatherResp.Temperature, weatherResp.Description, weatherResp.Humidity, weatherResp.WindSpeed)
-		weatherData = append(weatherData, weatherInfo)
+		weatherData = append(weatherData, data)
 	}
 	if len(weatherData) == 0 {
 		message := "i could not get any weather data for any of the cities"
@@ -186,7 +244,20 @@ func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advi
 		return err
 	}
 
-	err := s.streamAdviceGeneration(stream.Context(), weatherData, stream)
+	geocodersByCity := make(map[string]string, len(weatherData))
+	for _, data := range weatherData {
+		geocodersByCity[data.Location] = data.ResolvedBy
+	}
+
+	var err error
+	switch req.Format {
+	case advisorpb.AdvisorRequest_ANSI_PANEL:
+		err = s.streamANSIPanels(weatherData, req.Units, geocodersByCity, stream)
+	case advisorpb.AdvisorRequest_JSON_STRUCTURED:
+		err = s.streamJSONStructured(stream.Context(), weatherData, req.Language, req.Units, geocodersByCity, stream)
+	default:
+		err = s.streamAdviceGeneration(stream.Context(), weatherData, req.Language, req.Units, geocodersByCity, stream)
+	}
 	if err != nil {
 		advisorRequests.WithLabelValues("error").Inc()
 		return fmt.Errorf("advice generation failed: %v", err)
@@ -196,9 +267,18 @@ func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advi
 	return nil
 }
 
-func (s *advisorService) streamAdviceGeneration(ctx context.Context, weatherData []string, stream advisorpb.AdvisorService_StreamAdviceServer) error {
+func (s *advisorService) streamAdviceGeneration(ctx context.Context, weatherData []*cityWeatherData, language, units string, geocodersByCity map[string]string, stream advisorpb.AdvisorService_StreamAdviceServer) error {
+	f := newUnitFormatter(units)
+	var sections strings.Builder
+	for _, data := range weatherData {
+		sections.WriteString(buildCitySection(data, f))
+	}
+
 	model := s.genaiClient.GenerativeModel("gemini-2.5-pro")
-	prompt := fmt.Sprintf(`Weather advisor. Based on this data provide practical advice: %s Include: summary, clothing advice, activity suggestions, warnings. Keep it concise.`, strings.Join(weatherData, "\n"))
+	prompt := fmt.Sprintf(`%sWeather advisor. Based on this data provide practical advice:
+
+%s
+Include: summary, clothing advice, activity suggestions, warnings. Use the Warnings sections verbatim rather than guessing at alerts. Keep it concise.`, languageInstruction(language), sections.String())
 
 	iter := model.GenerateContentStream(ctx, genai.Text(prompt))
 
@@ -208,8 +288,9 @@ func (s *advisorService) streamAdviceGeneration(ctx context.Context, weatherData
 			if strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "iterator stopped") {
 
 				return stream.Send(&advisorpb.StreamAdviceResponse{
-					Chunk:      "",
-					IsComplete: true,
+					Chunk:             "",
+					IsComplete:        true,
+					ResolvedGeocoders: geocodersByCity,
 				})
 			}
 			return fmt.Errorf("streaming failed: %v", err)
@@ -0,0 +1,77 @@
+package advisor
+
+import (
+	"context"
+	"fmt"
+
+	"weatherservices/shared/proto/advisorpb"
+)
+
+// supportedLanguages are the BCP-47 tags the advisor prompt has been tuned
+// to request responses in. Nominatim and Gemini both accept a much wider
+// set, but we only advertise the ones we've verified.
+var supportedLanguages = []string{"en", "es", "fr", "de", "pt", "hi", "ja", "zh"}
+
+// languageInstruction builds the system-style preamble Gemini is asked to
+// follow so advice comes back in the caller's requested language. It's
+// prepended to every prompt rather than sent as a separate system message
+// since the genai client here doesn't set one up.
+func languageInstruction(language string) string {
+	if language == "" || language == defaultLanguage {
+		return ""
+	}
+	return fmt.Sprintf("Respond entirely in the language with BCP-47 tag %q.\n\n", language)
+}
+
+// ListSupportedLanguages returns the BCP-47 language tags the advisor can
+// be asked to respond in.
+func (s *advisorService) ListSupportedLanguages(_ context.Context, _ *advisorpb.ListSupportedLanguagesRequest) (*advisorpb.ListSupportedLanguagesResponse, error) {
+	return &advisorpb.ListSupportedLanguagesResponse{Languages: supportedLanguages}, nil
+}
+
+// unitFormatter converts the metric values our providers return (°C, m/s)
+// into whatever unit system the caller asked for. The zero value formats
+// as metric, matching the service's previous hardcoded behavior.
+type unitFormatter struct {
+	system string // "metric", "imperial", or "standard" (Kelvin)
+}
+
+func newUnitFormatter(units string) *unitFormatter {
+	switch units {
+	case "imperial", "standard":
+		return &unitFormatter{system: units}
+	default:
+		return &unitFormatter{system: "metric"}
+	}
+}
+
+// temperature converts a Celsius reading and returns it alongside its unit
+// suffix, e.g. (68.0, "°F").
+func (f *unitFormatter) temperature(celsius float64) (float64, string) {
+	switch f.system {
+	case "imperial":
+		return celsius*9/5 + 32, "°F"
+	case "standard":
+		return celsius + 273.15, "K"
+	default:
+		return celsius, "°C"
+	}
+}
+
+// speed converts a m/s reading and returns it alongside its unit suffix.
+func (f *unitFormatter) speed(metersPerSecond float64) (float64, string) {
+	if f.system == "imperial" {
+		return metersPerSecond * 2.23694, "mph"
+	}
+	return metersPerSecond, "m/s"
+}
+
+func (f *unitFormatter) formatTemperature(celsius float64) string {
+	value, unit := f.temperature(celsius)
+	return fmt.Sprintf("%.1f%s", value, unit)
+}
+
+func (f *unitFormatter) formatSpeed(metersPerSecond float64) string {
+	value, unit := f.speed(metersPerSecond)
+	return fmt.Sprintf("%.1f %s", value, unit)
+}